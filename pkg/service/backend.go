@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"runtime"
+)
+
+// ServiceBackend abstracts the init system used to manage a Service so that
+// this module is not pinned to Debian+systemd hosts.
+type ServiceBackend interface {
+	Status(s Service) (string, error)
+	Install(s Service) (string, error)
+	Remove(s Service) (string, error)
+	Start(s Service) (string, error)
+	Stop(s Service) (string, error)
+	Enable(s Service) (string, error)
+	Disable(s Service) (string, error)
+	Reload(s Service) (string, error)
+	Version(s Service) (*string, error)
+}
+
+var backend = detectBackend()
+
+// SetBackend overrides the autodetected ServiceBackend
+func SetBackend(b ServiceBackend) {
+	backend = b
+}
+
+// detectBackend picks a ServiceBackend based on the SERVICE_BACKEND env
+// variable, runtime.GOOS and, on Linux, a probe for a running systemd
+func detectBackend() ServiceBackend {
+	switch os.Getenv("SERVICE_BACKEND") {
+	case "systemd":
+		return systemdBackend{}
+	case "openrc":
+		return openrcBackend{}
+	case "sysvinit":
+		return sysvinitBackend{}
+	case "launchd":
+		return launchdBackend{}
+	case "windows":
+		return windowsBackend{}
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		return launchdBackend{}
+	case "windows":
+		return windowsBackend{}
+	}
+
+	if hasSystemd() {
+		return systemdBackend{}
+	}
+	if hasOpenRC() {
+		return openrcBackend{}
+	}
+	return sysvinitBackend{}
+}
+
+// hasSystemd reports whether the host is running under systemd
+func hasSystemd() bool {
+	_, err := os.Stat("/run/systemd/system")
+	return err == nil
+}
+
+// hasOpenRC reports whether the host manages services through OpenRC, as
+// opposed to a plain sysvinit layout
+func hasOpenRC() bool {
+	return binAvailable("rc-service")
+}
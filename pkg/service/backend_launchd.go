@@ -0,0 +1,70 @@
+package service
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// launchdBackend manages services through launchctl, for macOS targets
+type launchdBackend struct{}
+
+func (launchdBackend) label(s Service) string {
+	return "com.energieip." + s.Name
+}
+
+func (b launchdBackend) Status(s Service) (string, error) {
+	out, err := exec.Command("launchctl", "list", b.label(s)).CombinedOutput()
+	if err != nil {
+		return ServiceMissing, nil
+	}
+	if strings.Contains(string(out), "\"PID\"") {
+		return ServiceRunning, nil
+	}
+	return ServiceStop, nil
+}
+
+func (launchdBackend) Install(s Service) (string, error) {
+	cmd := exec.Command("brew", "install", s.PackageName)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (launchdBackend) Remove(s Service) (string, error) {
+	cmd := exec.Command("brew", "uninstall", s.PackageName)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (b launchdBackend) Start(s Service) (string, error) {
+	cmd := exec.Command("launchctl", "start", b.label(s))
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (b launchdBackend) Stop(s Service) (string, error) {
+	cmd := exec.Command("launchctl", "stop", b.label(s))
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (b launchdBackend) Enable(s Service) (string, error) {
+	cmd := exec.Command("launchctl", "enable", b.label(s))
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (b launchdBackend) Disable(s Service) (string, error) {
+	cmd := exec.Command("launchctl", "disable", b.label(s))
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (b launchdBackend) Reload(s Service) (string, error) {
+	cmd := exec.Command("launchctl", "kickstart", "-k", b.label(s))
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (launchdBackend) Version(s Service) (*string, error) {
+	return nil, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// openrcBackend manages services through rc-service/rc-update, covering
+// OpenRC distributions such as Alpine and Gentoo. Classic sysvinit hosts
+// (no rc-service/rc-update) are handled separately by sysvinitBackend
+type openrcBackend struct{}
+
+func (openrcBackend) Status(s Service) (string, error) {
+	out, err := exec.Command("rc-service", s.Name, "status").CombinedOutput()
+	output := strings.TrimSpace(string(out))
+	if err != nil {
+		if strings.Contains(output, "does not exist") {
+			return ServiceMissing, nil
+		}
+		return ServiceFailed, nil
+	}
+	if strings.Contains(output, "started") {
+		return ServiceRunning, nil
+	}
+	return ServiceStop, nil
+}
+
+func (openrcBackend) Install(s Service) (string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return "", errNoPackageManager
+	}
+	return pm.Install(s.PackageName)
+}
+
+func (openrcBackend) Remove(s Service) (string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return "", errNoPackageManager
+	}
+	return pm.Remove(s.PackageName)
+}
+
+func (openrcBackend) Start(s Service) (string, error) {
+	cmd := exec.Command("rc-service", s.Name, "start")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (openrcBackend) Stop(s Service) (string, error) {
+	cmd := exec.Command("rc-service", s.Name, "stop")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (openrcBackend) Enable(s Service) (string, error) {
+	cmd := exec.Command("rc-update", "add", s.Name, "default")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (openrcBackend) Disable(s Service) (string, error) {
+	cmd := exec.Command("rc-update", "del", s.Name, "default")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (openrcBackend) Reload(s Service) (string, error) {
+	cmd := exec.Command("rc-service", s.Name, "reload")
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (openrcBackend) Version(s Service) (*string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return nil, errNoPackageManager
+	}
+	return pm.Version(s.PackageName), nil
+}
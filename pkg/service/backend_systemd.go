@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// systemdBackend manages services through systemctl, the historical
+// behavior of this module; packages are installed, removed and versioned
+// through the PackageManager selected for the service
+type systemdBackend struct{}
+
+func (systemdBackend) Status(s Service) (string, error) {
+	outputActive := &bytes.Buffer{}
+	cmd := exec.Command("systemctl", "is-active", s.Name)
+	cmd.Stdout = outputActive
+	cmd.Run()
+	output := strings.TrimSpace(outputActive.String())
+	switch output {
+	case "failed":
+		return ServiceFailed, nil
+	case "active":
+		return ServiceRunning, nil
+	default:
+		outputEnable := &bytes.Buffer{}
+		cmd = exec.Command("systemctl", "is-enabled", s.Name)
+		cmd.Stdout = outputEnable
+		cmd.Run()
+		output = strings.TrimSpace(outputEnable.String())
+		if output == "disabled" {
+			return ServiceStop, nil
+		}
+		return ServiceMissing, nil
+	}
+}
+
+func (systemdBackend) Install(s Service) (string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return "", errNoPackageManager
+	}
+	return pm.Install(s.PackageName)
+}
+
+func (systemdBackend) Remove(s Service) (string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return "", errNoPackageManager
+	}
+	return pm.Remove(s.PackageName)
+}
+
+func (systemdBackend) Start(s Service) (string, error) {
+	cmd := exec.Command("systemctl", "start", s.Name)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (systemdBackend) Stop(s Service) (string, error) {
+	cmd := exec.Command("systemctl", "stop", s.Name)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (systemdBackend) Enable(s Service) (string, error) {
+	cmd := exec.Command("systemctl", "enable", s.Name)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (systemdBackend) Disable(s Service) (string, error) {
+	cmd := exec.Command("systemctl", "disable", s.Name)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (systemdBackend) Reload(s Service) (string, error) {
+	cmd := exec.Command("systemctl", "reload", s.Name)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+func (systemdBackend) Version(s Service) (*string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return nil, errNoPackageManager
+	}
+	return pm.Version(s.PackageName), nil
+}
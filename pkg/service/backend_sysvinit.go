@@ -0,0 +1,81 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// sysvinitBackend manages services through the classic /etc/init.d
+// scripts and the distribution-agnostic `service`/`update-rc.d` wrappers,
+// covering sysvinit hosts that have neither systemd nor OpenRC (e.g. a
+// minimal Debian install without systemd)
+type sysvinitBackend struct{}
+
+func (sysvinitBackend) initScript(s Service) string {
+	return "/etc/init.d/" + s.Name
+}
+
+func (b sysvinitBackend) Status(s Service) (string, error) {
+	if _, err := os.Stat(b.initScript(s)); err != nil {
+		return ServiceMissing, nil
+	}
+	out, err := exec.Command("service", s.Name, "status").CombinedOutput()
+	output := strings.ToLower(strings.TrimSpace(string(out)))
+	if err != nil {
+		return ServiceFailed, nil
+	}
+	if strings.Contains(output, "running") {
+		return ServiceRunning, nil
+	}
+	return ServiceStop, nil
+}
+
+func (sysvinitBackend) Install(s Service) (string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return "", errNoPackageManager
+	}
+	return pm.Install(s.PackageName)
+}
+
+func (sysvinitBackend) Remove(s Service) (string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return "", errNoPackageManager
+	}
+	return pm.Remove(s.PackageName)
+}
+
+func (sysvinitBackend) Start(s Service) (string, error) {
+	out, err := exec.Command("service", s.Name, "start").CombinedOutput()
+	return string(out), err
+}
+
+func (sysvinitBackend) Stop(s Service) (string, error) {
+	out, err := exec.Command("service", s.Name, "stop").CombinedOutput()
+	return string(out), err
+}
+
+func (sysvinitBackend) Enable(s Service) (string, error) {
+	out, err := exec.Command("update-rc.d", s.Name, "defaults").CombinedOutput()
+	return string(out), err
+}
+
+func (sysvinitBackend) Disable(s Service) (string, error) {
+	out, err := exec.Command("update-rc.d", s.Name, "disable").CombinedOutput()
+	return string(out), err
+}
+
+func (sysvinitBackend) Reload(s Service) (string, error) {
+	out, err := exec.Command("service", s.Name, "reload").CombinedOutput()
+	return string(out), err
+}
+
+func (sysvinitBackend) Version(s Service) (*string, error) {
+	pm := packageManagerFor(s)
+	if pm == nil {
+		return nil, errNoPackageManager
+	}
+	return pm.Version(s.PackageName), nil
+}
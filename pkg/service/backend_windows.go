@@ -0,0 +1,165 @@
+//go:build windows
+
+package service
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// windowsBackend manages services through the Windows Service Control
+// Manager
+type windowsBackend struct{}
+
+func (windowsBackend) Status(s Service) (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return ServiceMissing, nil
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.OpenService(s.Name)
+	if err != nil {
+		return ServiceMissing, nil
+	}
+	defer svcHandle.Close()
+
+	status, err := svcHandle.Query()
+	if err != nil {
+		return ServiceFailed, err
+	}
+	switch status.State {
+	case svc.Running:
+		return ServiceRunning, nil
+	case svc.Stopped:
+		return ServiceStop, nil
+	default:
+		return ServiceFailed, nil
+	}
+}
+
+func (windowsBackend) Install(s Service) (string, error) {
+	if s.PackageName == "" {
+		return "", errors.New("windows service backend requires Service.PackageName to hold the path to the service executable")
+	}
+
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.CreateService(s.Name, s.PackageName, mgr.Config{
+		DisplayName: s.Name,
+		StartType:   mgr.StartAutomatic,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer svcHandle.Close()
+	return "", nil
+}
+
+func (windowsBackend) Remove(s Service) (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.OpenService(s.Name)
+	if err != nil {
+		return "", err
+	}
+	defer svcHandle.Close()
+
+	return "", svcHandle.Delete()
+}
+
+func (windowsBackend) Start(s Service) (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.OpenService(s.Name)
+	if err != nil {
+		return "", err
+	}
+	defer svcHandle.Close()
+
+	return "", svcHandle.Start()
+}
+
+func (windowsBackend) Stop(s Service) (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.OpenService(s.Name)
+	if err != nil {
+		return "", err
+	}
+	defer svcHandle.Close()
+
+	_, err = svcHandle.Control(svc.Stop)
+	return "", err
+}
+
+func (windowsBackend) Enable(s Service) (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.OpenService(s.Name)
+	if err != nil {
+		return "", err
+	}
+	defer svcHandle.Close()
+
+	cfg, err := svcHandle.Config()
+	if err != nil {
+		return "", err
+	}
+	cfg.StartType = mgr.StartAutomatic
+	return "", svcHandle.UpdateConfig(cfg)
+}
+
+func (windowsBackend) Disable(s Service) (string, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return "", err
+	}
+	defer m.Disconnect()
+
+	svcHandle, err := m.OpenService(s.Name)
+	if err != nil {
+		return "", err
+	}
+	defer svcHandle.Close()
+
+	cfg, err := svcHandle.Config()
+	if err != nil {
+		return "", err
+	}
+	cfg.StartType = mgr.StartDisabled
+	return "", svcHandle.UpdateConfig(cfg)
+}
+
+func (b windowsBackend) Reload(s Service) (string, error) {
+	if _, err := b.Stop(s); err != nil {
+		return "", err
+	}
+	return b.Start(s)
+}
+
+func (windowsBackend) Version(s Service) (*string, error) {
+	return nil, nil
+}
@@ -0,0 +1,21 @@
+//go:build !windows
+
+package service
+
+import "errors"
+
+// windowsBackend is a stub on non-Windows platforms; the real
+// implementation lives in backend_windows.go
+type windowsBackend struct{}
+
+var errWindowsOnly = errors.New("windows service backend is only available on windows")
+
+func (windowsBackend) Status(s Service) (string, error)   { return ServiceMissing, errWindowsOnly }
+func (windowsBackend) Install(s Service) (string, error)  { return "", errWindowsOnly }
+func (windowsBackend) Remove(s Service) (string, error)   { return "", errWindowsOnly }
+func (windowsBackend) Start(s Service) (string, error)    { return "", errWindowsOnly }
+func (windowsBackend) Stop(s Service) (string, error)     { return "", errWindowsOnly }
+func (windowsBackend) Enable(s Service) (string, error)   { return "", errWindowsOnly }
+func (windowsBackend) Disable(s Service) (string, error)  { return "", errWindowsOnly }
+func (windowsBackend) Reload(s Service) (string, error)   { return "", errWindowsOnly }
+func (windowsBackend) Version(s Service) (*string, error) { return nil, errWindowsOnly }
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// PatchOp is a single RFC 6902 JSON Patch operation
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ConfigPatch is an RFC 6902 JSON Patch document describing the
+// difference between two ServiceConfig values
+type ConfigPatch []PatchOp
+
+// Diff returns the ConfigPatch that turns config into other, covering
+// only the fields that actually changed. Both sides are compared with
+// their secret:// references restored, so the patch (and anything it is
+// printed to, see ConfigPatch.Print) never carries resolved plaintext
+// credentials
+func (config ServiceConfig) Diff(other ServiceConfig) (ConfigPatch, error) {
+	a, err := configToTree(config.withSecretRefsRestored())
+	if err != nil {
+		return nil, err
+	}
+	b, err := configToTree(other.withSecretRefsRestored())
+	if err != nil {
+		return nil, err
+	}
+
+	var patch ConfigPatch
+	diffTree("", a, b, &patch)
+	return patch, nil
+}
+
+// Apply returns a copy of config with patch applied. Like Diff, it
+// operates on the secret-ref-preserving representation of config, and
+// re-resolves secret:// references in the result so the returned
+// ServiceConfig is immediately usable and WriteServiceConfig will still
+// persist references rather than plaintext
+func (config ServiceConfig) Apply(patch ConfigPatch) (ServiceConfig, error) {
+	tree, err := configToTree(config.withSecretRefsRestored())
+	if err != nil {
+		return ServiceConfig{}, err
+	}
+
+	for _, op := range patch {
+		switch op.Op {
+		case "add", "replace":
+			if err := setPointer(tree, op.Path, op.Value); err != nil {
+				return ServiceConfig{}, fmt.Errorf("applying %s %s: %w", op.Op, op.Path, err)
+			}
+		case "remove":
+			if err := removePointer(tree, op.Path); err != nil {
+				return ServiceConfig{}, fmt.Errorf("applying remove %s: %w", op.Path, err)
+			}
+		default:
+			return ServiceConfig{}, fmt.Errorf("unsupported patch operation %q", op.Op)
+		}
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return ServiceConfig{}, err
+	}
+	var result ServiceConfig
+	if err := json.Unmarshal(data, &result); err != nil {
+		return ServiceConfig{}, err
+	}
+	if err := resolveConfigSecrets(&result); err != nil {
+		return ServiceConfig{}, err
+	}
+	return result, nil
+}
+
+// Fprint writes patch to w as indented JSON, in the style of a
+// config-diff tool an operator can review before a rollout
+func (patch ConfigPatch) Fprint(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(patch)
+}
+
+// Print writes patch to stdout, see Fprint
+func (patch ConfigPatch) Print() error {
+	return patch.Fprint(os.Stdout)
+}
+
+func configToTree(config ServiceConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// diffTree recursively compares a and b, appending ops to patch. Objects
+// are compared key by key; any other value (including arrays) that
+// differs is replaced wholesale
+func diffTree(path string, a, b interface{}, patch *ConfigPatch) {
+	amap, aIsMap := a.(map[string]interface{})
+	bmap, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		for key, bval := range bmap {
+			childPath := path + "/" + escapePointerSegment(key)
+			aval, exists := amap[key]
+			if !exists {
+				*patch = append(*patch, PatchOp{Op: "add", Path: childPath, Value: bval})
+				continue
+			}
+			diffTree(childPath, aval, bval, patch)
+		}
+		for key := range amap {
+			if _, exists := bmap[key]; !exists {
+				*patch = append(*patch, PatchOp{Op: "remove", Path: path + "/" + escapePointerSegment(key)})
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*patch = append(*patch, PatchOp{Op: "replace", Path: path, Value: b})
+	}
+}
+
+func escapePointerSegment(segment string) string {
+	segment = strings.ReplaceAll(segment, "~", "~0")
+	segment = strings.ReplaceAll(segment, "/", "~1")
+	return segment
+}
+
+func unescapePointerSegment(segment string) string {
+	segment = strings.ReplaceAll(segment, "~1", "/")
+	segment = strings.ReplaceAll(segment, "~0", "~")
+	return segment
+}
+
+func splitPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("malformed JSON pointer %q", pointer)
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = unescapePointerSegment(segment)
+	}
+	return segments, nil
+}
+
+func setPointer(tree map[string]interface{}, pointer string, value interface{}) error {
+	segments, err := splitPointer(pointer)
+	if err != nil {
+		return err
+	}
+	node := tree
+	for _, key := range segments[:len(segments)-1] {
+		child, ok := node[key].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			node[key] = child
+		}
+		node = child
+	}
+	node[segments[len(segments)-1]] = value
+	return nil
+}
+
+func removePointer(tree map[string]interface{}, pointer string) error {
+	segments, err := splitPointer(pointer)
+	if err != nil {
+		return err
+	}
+	node := tree
+	for _, key := range segments[:len(segments)-1] {
+		child, ok := node[key].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("path %q does not exist", pointer)
+		}
+		node = child
+	}
+	delete(node, segments[len(segments)-1])
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func testConfig() ServiceConfig {
+	return ServiceConfig{
+		LocalBroker: Broker{
+			IP:       "127.0.0.1",
+			Port:     "1883",
+			Login:    "local",
+			Password: "localpass",
+		},
+		NetworkBroker: Broker{
+			IP:       "10.0.0.1",
+			Port:     "1883",
+			Login:    "network",
+			Password: "networkpass",
+		},
+		DB: DBConnector{
+			ClientIP:   "10.0.0.2",
+			ClientPort: "8086",
+			DBCluster: Cluster{
+				Connectors: []Connector{{IP: "10.0.0.3", Port: "9042"}},
+			},
+		},
+		LogLevel: "INFO",
+	}
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	config := testConfig()
+	patch, err := config.Diff(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(patch) != 0 {
+		t.Fatalf("expected an empty patch, got %+v", patch)
+	}
+}
+
+func TestDiffApplyRoundTrip(t *testing.T) {
+	before := testConfig()
+	after := testConfig()
+	after.LogLevel = "DEBUG"
+	after.LocalBroker.Password = "rotated"
+	after.DB.DBCluster.Connectors = append(after.DB.DBCluster.Connectors, Connector{IP: "10.0.0.4", Port: "9043"})
+
+	patch, err := before.Diff(after)
+	if err != nil {
+		t.Fatalf("unexpected error computing diff: %v", err)
+	}
+	if len(patch) == 0 {
+		t.Fatal("expected a non-empty patch")
+	}
+
+	applied, err := before.Apply(patch)
+	if err != nil {
+		t.Fatalf("unexpected error applying patch: %v", err)
+	}
+	if !reflect.DeepEqual(applied, after) {
+		t.Fatalf("expected Apply(Diff(before, after)) == after\ngot:  %+v\nwant: %+v", applied, after)
+	}
+}
+
+func TestDiffPreservesSecretReferences(t *testing.T) {
+	os.Setenv("TEST_ENERGIEIP_DIFF_SECRET", "plaintext-password")
+	defer os.Unsetenv("TEST_ENERGIEIP_DIFF_SECRET")
+
+	before := testConfig()
+	before.LocalBroker.Password = "secret://env/TEST_ENERGIEIP_DIFF_SECRET"
+	if err := resolveConfigSecrets(&before); err != nil {
+		t.Fatalf("unexpected error resolving secrets: %v", err)
+	}
+	if before.LocalBroker.Password != "plaintext-password" {
+		t.Fatalf("expected the password to be resolved, got %q", before.LocalBroker.Password)
+	}
+
+	after := before
+	after.LogLevel = "DEBUG"
+
+	patch, err := before.Diff(after)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range patch {
+		if s, ok := op.Value.(string); ok && s == "plaintext-password" {
+			t.Fatalf("patch leaked a resolved secret: %+v", patch)
+		}
+	}
+
+	applied, err := before.Apply(patch)
+	if err != nil {
+		t.Fatalf("unexpected error applying patch: %v", err)
+	}
+	if applied.LocalBroker.Password != "plaintext-password" {
+		t.Fatalf("expected Apply to re-resolve the secret reference, got %q", applied.LocalBroker.Password)
+	}
+	if applied.secretRefs["/localBroker/password"] != "secret://env/TEST_ENERGIEIP_DIFF_SECRET" {
+		t.Fatalf("expected Apply to remember the secret reference, got %+v", applied.secretRefs)
+	}
+}
+
+func TestPointerSegmentEscaping(t *testing.T) {
+	escaped := escapePointerSegment("a/b~c")
+	if escaped != "a~1b~0c" {
+		t.Fatalf("expected a~1b~0c, got %q", escaped)
+	}
+	if unescapePointerSegment(escaped) != "a/b~c" {
+		t.Fatalf("expected unescape to round-trip, got %q", unescapePointerSegment(escaped))
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"github.com/fsnotify/fsnotify"
+)
+
+// ReloadServiceConfig watches path for writes and calls onChange with the
+// previous and new ServiceConfig whenever the diff between them is
+// non-empty, so that a cosmetic-only rewrite (e.g. key reordering) does
+// not trigger an unnecessary reload of dependent brokers. It blocks until
+// the watcher is closed or an unrecoverable error occurs
+func ReloadServiceConfig(path string, onChange func(old, new ServiceConfig) error) error {
+	current, err := ReadServiceConfig(path)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(path); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			updated, err := ReadServiceConfig(path)
+			if err != nil {
+				continue
+			}
+
+			patch, err := current.Diff(*updated)
+			if err != nil || len(patch) == 0 {
+				continue
+			}
+
+			old := *current
+			current = updated
+			if err := onChange(old, *current); err != nil {
+				return err
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		}
+	}
+}
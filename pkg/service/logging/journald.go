@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+// journaldHandler is a slog.Handler that forwards records to journald
+// with the right priority, so `systemctl status` renders them with
+// correctly colored severities
+type journaldHandler struct {
+	level slog.Leveler
+	attrs []slog.Attr
+}
+
+func newJournaldHandler(level slog.Leveler) *journaldHandler {
+	return &journaldHandler{level: level}
+}
+
+func (h *journaldHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *journaldHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := make(map[string]string, len(h.attrs)+record.NumAttrs())
+	for _, attr := range h.attrs {
+		fields[attr.Key] = attr.Value.String()
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		fields[attr.Key] = attr.Value.String()
+		return true
+	})
+	return journal.Send(record.Message, levelToPriority(record.Level), fields)
+}
+
+func (h *journaldHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &clone
+}
+
+func (h *journaldHandler) WithGroup(_ string) slog.Handler {
+	// journald entries are flat, so groups collapse into the parent handler
+	return h
+}
+
+func levelToPriority(level slog.Level) journal.Priority {
+	switch {
+	case level >= LevelCritical:
+		return journal.PriCrit
+	case level >= slog.LevelError:
+		return journal.PriErr
+	case level >= slog.LevelWarn:
+		return journal.PriWarning
+	case level >= slog.LevelInfo:
+		return journal.PriInfo
+	default:
+		return journal.PriDebug
+	}
+}
@@ -0,0 +1,88 @@
+// Package logging builds a process-wide slog.Logger honoring a LogLevel
+// string, with a journald handler so that priorities show up correctly
+// under `systemctl status` when running as a unit. It is consumed by the
+// parent service package's ConfigureLogger/Logger to avoid an import
+// cycle with service.ServiceConfig
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// LevelCritical has no log/slog equivalent, so it is mapped one step
+// above LevelError
+const LevelCritical = slog.LevelError + 4
+
+// ParseLevel maps the free-form ServiceConfig.LogLevel string to a
+// slog.Level, understanding DEBUG/INFO/WARN/ERROR/CRITICAL and
+// defaulting to INFO for anything else
+func ParseLevel(level string) slog.Level {
+	switch strings.ToUpper(strings.TrimSpace(level)) {
+	case "DEBUG":
+		return slog.LevelDebug
+	case "WARN", "WARNING":
+		return slog.LevelWarn
+	case "ERROR":
+		return slog.LevelError
+	case "CRITICAL", "FATAL":
+		return LevelCritical
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// underSystemd reports whether the current process was started by
+// systemd, per systemd.exec(5)
+func underSystemd() bool {
+	return os.Getenv("INVOCATION_ID") != ""
+}
+
+// NewHandler builds a slog.Handler for the given style: "json", "text" or
+// "journald". An empty style autodetects journald when running under
+// systemd and falls back to text otherwise
+func NewHandler(style string, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	switch style {
+	case "json":
+		return slog.NewJSONHandler(os.Stderr, opts)
+	case "journald":
+		return newJournaldHandler(level)
+	case "text":
+		return slog.NewTextHandler(os.Stderr, opts)
+	default:
+		if underSystemd() {
+			return newJournaldHandler(level)
+		}
+		return slog.NewTextHandler(os.Stderr, opts)
+	}
+}
+
+// ConfigureFromLevel builds a *slog.Logger honoring the given LogLevel
+// string and sets it as the process-wide logger returned by Logger()
+func ConfigureFromLevel(level string) *slog.Logger {
+	logger := slog.New(NewHandler("", ParseLevel(level)))
+	SetLogger(logger)
+	return logger
+}
+
+var (
+	mu     sync.RWMutex
+	logger = slog.New(NewHandler("text", slog.LevelInfo))
+)
+
+// SetLogger overrides the process-wide logger returned by Logger()
+func SetLogger(l *slog.Logger) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger = l
+}
+
+// Logger returns the process-wide logger, see ConfigureFromLevel
+func Logger() *slog.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+	return logger
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+var errNoPackageManager = errors.New("no supported package manager found on this host")
+
+// PackageManager abstracts the tool used to install OS packages so that
+// this module is not pinned to apt-based distributions
+type PackageManager interface {
+	Install(pkg string) (string, error)
+	Remove(pkg string) (string, error)
+	Version(pkg string) *string
+	Available() bool
+}
+
+// DetectPackageManager probes $PATH for a supported package manager and
+// returns the first one found, in the order apt, dpkg, dnf, yum, pacman,
+// apk, snap
+func DetectPackageManager() PackageManager {
+	candidates := []PackageManager{
+		aptPackageManager{},
+		dpkgPackageManager{},
+		dnfPackageManager{},
+		pacmanPackageManager{},
+		apkPackageManager{},
+		snapPackageManager{},
+	}
+	for _, candidate := range candidates {
+		if candidate.Available() {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// packageManagerFor resolves the PackageManager to use for a service,
+// honoring Service.PackageManager as an override over autodetection
+func packageManagerFor(s Service) PackageManager {
+	switch s.PackageManager {
+	case "apt":
+		return aptPackageManager{}
+	case "dpkg":
+		return dpkgPackageManager{}
+	case "dnf", "yum":
+		return dnfPackageManager{}
+	case "pacman":
+		return pacmanPackageManager{}
+	case "apk":
+		return apkPackageManager{}
+	case "snap":
+		return snapPackageManager{}
+	}
+	return DetectPackageManager()
+}
+
+func binAvailable(bin string) bool {
+	_, err := exec.LookPath(bin)
+	return err == nil
+}
+
+func versionFromOutput(output string, prefix string, sep string) *string {
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		if !strings.HasPrefix(line, prefix) {
+			continue
+		}
+		fields := strings.Split(line, sep)
+		if len(fields) > 1 {
+			version := strings.TrimSpace(fields[1])
+			return &version
+		}
+	}
+	return nil
+}
+
+// aptPackageManager installs packages through apt-get, the historical
+// behavior of this module
+type aptPackageManager struct{}
+
+func (aptPackageManager) Available() bool { return binAvailable("apt-get") }
+
+func (aptPackageManager) Install(pkg string) (string, error) {
+	out, err := exec.Command("apt-get", "install", "-y", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (aptPackageManager) Remove(pkg string) (string, error) {
+	out, err := exec.Command("apt-get", "remove", "-y", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (aptPackageManager) Version(pkg string) *string {
+	out, err := exec.Command("apt", "show", pkg).CombinedOutput()
+	if err != nil {
+		return nil
+	}
+	return versionFromOutput(string(out), "Version:", " ")
+}
+
+// dpkgPackageManager installs from local .deb files via dpkg, for hosts
+// without network access to an apt repository
+type dpkgPackageManager struct{}
+
+func (dpkgPackageManager) Available() bool { return !binAvailable("apt-get") && binAvailable("dpkg") }
+
+func (dpkgPackageManager) Install(pkg string) (string, error) {
+	out, err := exec.Command("dpkg", "-i", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (dpkgPackageManager) Remove(pkg string) (string, error) {
+	out, err := exec.Command("dpkg", "-r", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (dpkgPackageManager) Version(pkg string) *string {
+	out, err := exec.Command("dpkg-query", "-W", "-f=${Version}", pkg).CombinedOutput()
+	if err != nil {
+		return nil
+	}
+	version := strings.TrimSpace(string(out))
+	return &version
+}
+
+// dnfPackageManager installs packages through dnf, falling back to yum,
+// for Fedora/RHEL/CentOS targets
+type dnfPackageManager struct{}
+
+func (dnfPackageManager) bin() string {
+	if binAvailable("dnf") {
+		return "dnf"
+	}
+	return "yum"
+}
+
+func (m dnfPackageManager) Available() bool { return binAvailable("dnf") || binAvailable("yum") }
+
+func (m dnfPackageManager) Install(pkg string) (string, error) {
+	out, err := exec.Command(m.bin(), "install", "-y", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (m dnfPackageManager) Remove(pkg string) (string, error) {
+	out, err := exec.Command(m.bin(), "remove", "-y", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (m dnfPackageManager) Version(pkg string) *string {
+	out, err := exec.Command(m.bin(), "info", pkg).CombinedOutput()
+	if err != nil {
+		return nil
+	}
+	return versionFromOutput(string(out), "Version", ":")
+}
+
+// pacmanPackageManager installs packages through pacman, for Arch Linux
+// targets
+type pacmanPackageManager struct{}
+
+func (pacmanPackageManager) Available() bool { return binAvailable("pacman") }
+
+func (pacmanPackageManager) Install(pkg string) (string, error) {
+	out, err := exec.Command("pacman", "-S", "--noconfirm", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (pacmanPackageManager) Remove(pkg string) (string, error) {
+	out, err := exec.Command("pacman", "-R", "--noconfirm", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (pacmanPackageManager) Version(pkg string) *string {
+	out, err := exec.Command("pacman", "-Q", pkg).CombinedOutput()
+	if err != nil {
+		return nil
+	}
+	fields := strings.Fields(strings.TrimSpace(string(out)))
+	if len(fields) > 1 {
+		return &fields[1]
+	}
+	return nil
+}
+
+// apkPackageManager installs packages through apk, for Alpine Linux
+// targets
+type apkPackageManager struct{}
+
+func (apkPackageManager) Available() bool { return binAvailable("apk") }
+
+func (apkPackageManager) Install(pkg string) (string, error) {
+	out, err := exec.Command("apk", "add", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (apkPackageManager) Remove(pkg string) (string, error) {
+	out, err := exec.Command("apk", "del", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (apkPackageManager) Version(pkg string) *string {
+	out, err := exec.Command("apk", "info", "-e", "-v", pkg).CombinedOutput()
+	if err != nil {
+		return nil
+	}
+	version := strings.TrimSpace(string(out))
+	if version == "" {
+		return nil
+	}
+	return &version
+}
+
+// snapPackageManager installs packages through snap, for Ubuntu Core and
+// other snap-only targets
+type snapPackageManager struct{}
+
+func (snapPackageManager) Available() bool { return binAvailable("snap") }
+
+func (snapPackageManager) Install(pkg string) (string, error) {
+	out, err := exec.Command("snap", "install", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (snapPackageManager) Remove(pkg string) (string, error) {
+	out, err := exec.Command("snap", "remove", pkg).CombinedOutput()
+	return string(out), err
+}
+
+func (snapPackageManager) Version(pkg string) *string {
+	out, err := exec.Command("snap", "list", pkg).CombinedOutput()
+	if err != nil {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) > 1 {
+		return &fields[1]
+	}
+	return nil
+}
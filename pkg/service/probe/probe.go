@@ -0,0 +1,119 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/energieip/common-service-go/pkg/service"
+)
+
+// Prober serves the aggregate status of a set of services over HTTP,
+// refreshing its cache on a fixed interval so that GetServiceStatus is
+// not invoked on every request
+type Prober struct {
+	services map[string]service.Service
+	interval time.Duration
+
+	mu    sync.RWMutex
+	cache []service.ServiceStatus
+}
+
+// NewProber creates a Prober for services and fills its status cache
+// once; call Run to refresh the cache every interval
+func NewProber(services map[string]service.Service, interval time.Duration) *Prober {
+	p := &Prober{
+		services: services,
+		interval: interval,
+	}
+	p.refresh()
+	return p
+}
+
+// Run refreshes the status cache every interval until stop is closed
+func (p *Prober) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (p *Prober) refresh() {
+	statuses := make([]service.ServiceStatus, 0, len(p.services))
+	for _, svc := range p.services {
+		status := svc.GetServiceStatus()
+		svc.Config = svc.Config.Redacted()
+		statuses = append(statuses, service.ServiceStatus{
+			Service: svc,
+			Status:  &status,
+		})
+	}
+
+	p.mu.Lock()
+	p.cache = statuses
+	p.mu.Unlock()
+}
+
+// Statuses returns the cached ServiceStatus for every registered service
+func (p *Prober) Statuses() []service.ServiceStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cache
+}
+
+func (p *Prober) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p.Statuses())
+}
+
+func (p *Prober) handleHealth(w http.ResponseWriter, r *http.Request) {
+	for _, status := range p.Statuses() {
+		if status.Status == nil || *status.Status != service.ServiceRunning {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (p *Prober) handleReady(w http.ResponseWriter, r *http.Request) {
+	for _, status := range p.Statuses() {
+		if status.Status == nil || *status.Status == service.ServiceMissing {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Mux returns an http.Handler exposing /health, /ready and /status
+func (p *Prober) Mux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", p.handleHealth)
+	mux.HandleFunc("/ready", p.handleReady)
+	mux.HandleFunc("/status", p.handleStatus)
+	return mux
+}
+
+// ListenAndServe starts the probe HTTP server on addr. When certPath and
+// keyPath are both set, the server is started over TLS using them as the
+// probe's own server certificate and key; these are deliberately separate
+// from service.Broker.CaPath, which elsewhere in this module names the CA
+// used to verify a broker, not a server certificate to present
+func (p *Prober) ListenAndServe(addr string, certPath string, keyPath string) error {
+	server := &http.Server{
+		Addr:    addr,
+		Handler: p.Mux(),
+	}
+	if certPath != "" && keyPath != "" {
+		return server.ListenAndServeTLS(certPath, keyPath)
+	}
+	return server.ListenAndServe()
+}
@@ -0,0 +1,65 @@
+package probe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/energieip/common-service-go/pkg/service"
+)
+
+func newProber(statuses []service.ServiceStatus) *Prober {
+	p := &Prober{interval: time.Minute}
+	p.cache = statuses
+	return p
+}
+
+func status(s string) *string { return &s }
+
+func TestHandleHealthAllRunning(t *testing.T) {
+	p := newProber([]service.ServiceStatus{
+		{Service: service.Service{Name: "a"}, Status: status(service.ServiceRunning)},
+		{Service: service.Service{Name: "b"}, Status: status(service.ServiceRunning)},
+	})
+
+	rec := httptest.NewRecorder()
+	p.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestHandleHealthOneStopped(t *testing.T) {
+	p := newProber([]service.ServiceStatus{
+		{Service: service.Service{Name: "a"}, Status: status(service.ServiceRunning)},
+		{Service: service.Service{Name: "b"}, Status: status(service.ServiceStop)},
+	})
+
+	rec := httptest.NewRecorder()
+	p.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+}
+
+func TestHandleReadyIgnoresStoppedButFlagsMissing(t *testing.T) {
+	p := newProber([]service.ServiceStatus{
+		{Service: service.Service{Name: "a"}, Status: status(service.ServiceStop)},
+	})
+
+	rec := httptest.NewRecorder()
+	p.handleReady(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a merely stopped service, got %d", rec.Code)
+	}
+
+	p = newProber([]service.ServiceStatus{
+		{Service: service.Service{Name: "a"}, Status: status(service.ServiceMissing)},
+	})
+	rec = httptest.NewRecorder()
+	p.handleReady(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for a missing service, got %d", rec.Code)
+	}
+}
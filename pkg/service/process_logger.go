@@ -0,0 +1,20 @@
+package service
+
+import (
+	"log/slog"
+
+	"github.com/energieip/common-service-go/pkg/service/logging"
+)
+
+// ConfigureLogger builds the process-wide logger from cfg.LogLevel; call
+// it once at startup, then use Logger() from anywhere in the process
+func ConfigureLogger(cfg ServiceConfig) *slog.Logger {
+	return logging.ConfigureFromLevel(cfg.LogLevel)
+}
+
+// Logger returns the process-wide logger configured by ConfigureLogger,
+// so that IService implementations get a logger honoring LogLevel without
+// importing service/logging themselves
+func Logger() *slog.Logger {
+	return logging.Logger()
+}
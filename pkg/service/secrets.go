@@ -0,0 +1,290 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const secretPrefix = "secret://"
+
+// SecretProvider resolves a secret://<provider>/<key> reference to its
+// plaintext value
+type SecretProvider interface {
+	Resolve(key string) (string, error)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"env":      envSecretProvider{},
+		"systemd":  systemdSecretProvider{},
+		"keystore": keystoreSecretProvider{},
+	}
+)
+
+// RegisterSecretProvider makes p available as secret://<scheme>/<key>,
+// overriding any provider already registered under scheme. This lets
+// callers plug in additional backends (Vault, a cloud KMS, ...) beyond
+// the built-in env/systemd/keystore providers
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[scheme] = p
+}
+
+// resolveSecret resolves value if it is a secret:// reference, otherwise
+// it is returned unchanged
+func resolveSecret(value string) (string, error) {
+	if !strings.HasPrefix(value, secretPrefix) {
+		return value, nil
+	}
+	ref := strings.TrimPrefix(value, secretPrefix)
+	parts := strings.SplitN(ref, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", fmt.Errorf("malformed secret reference %q", value)
+	}
+
+	secretProvidersMu.RLock()
+	provider, ok := secretProviders[parts[0]]
+	secretProvidersMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown secret provider %q", parts[0])
+	}
+	return provider.Resolve(parts[1])
+}
+
+// envSecretProvider resolves secret://env/NAME against the process
+// environment
+type envSecretProvider struct{}
+
+func (envSecretProvider) Resolve(key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", key)
+	}
+	return value, nil
+}
+
+// systemdSecretProvider resolves secret://systemd/NAME against the
+// credentials directory systemd exposes via LoadCredential=
+type systemdSecretProvider struct{}
+
+func (systemdSecretProvider) Resolve(key string) (string, error) {
+	dir := os.Getenv("CREDENTIALS_DIRECTORY")
+	if dir == "" {
+		return "", errors.New("CREDENTIALS_DIRECTORY is not set, are you running under systemd?")
+	}
+	value, err := ioutil.ReadFile(filepath.Join(dir, key))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(value), "\n"), nil
+}
+
+// keystoreSecretProvider resolves secret://keystore/NAME against a local
+// AES-GCM-encrypted keystore file. The master key is read from the file
+// referenced by ENERGIEIP_MASTER_KEY_FILE and the keystore itself from
+// ENERGIEIP_KEYSTORE_FILE (default /etc/energieip/keystore.json), a JSON
+// object mapping a key name to a base64-encoded nonce+ciphertext
+type keystoreSecretProvider struct{}
+
+func (keystoreSecretProvider) Resolve(key string) (string, error) {
+	masterKeyPath := os.Getenv("ENERGIEIP_MASTER_KEY_FILE")
+	if masterKeyPath == "" {
+		return "", errors.New("ENERGIEIP_MASTER_KEY_FILE is not set")
+	}
+	masterKey, err := ioutil.ReadFile(masterKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	storePath := os.Getenv("ENERGIEIP_KEYSTORE_FILE")
+	if storePath == "" {
+		storePath = "/etc/energieip/keystore.json"
+	}
+	raw, err := ioutil.ReadFile(storePath)
+	if err != nil {
+		return "", err
+	}
+
+	var store map[string]string
+	if err := json.Unmarshal(raw, &store); err != nil {
+		return "", err
+	}
+	sealed, ok := store[key]
+	if !ok {
+		return "", fmt.Errorf("secret %q not found in keystore %s", key, storePath)
+	}
+	return decryptAESGCM(strings.TrimSpace(string(masterKey)), sealed)
+}
+
+// decryptAESGCM decrypts a base64-encoded nonce+ciphertext blob with a
+// base64-encoded AES-GCM key
+func decryptAESGCM(b64Key string, b64Sealed string) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(b64Key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(b64Sealed)
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return "", errors.New("sealed secret is shorter than the AES-GCM nonce")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// walkResolveSecrets recursively resolves every secret:// string found
+// under value, recording the original reference against its JSON
+// pointer path in refs. Maps and slices are mutated in place; value
+// itself is returned so the top-level caller can reassign it
+func walkResolveSecrets(path string, value interface{}, refs map[string]string) (interface{}, error) {
+	switch node := value.(type) {
+	case map[string]interface{}:
+		for key, child := range node {
+			resolved, err := walkResolveSecrets(path+"/"+escapePointerSegment(key), child, refs)
+			if err != nil {
+				return nil, err
+			}
+			node[key] = resolved
+		}
+		return node, nil
+	case []interface{}:
+		for i, child := range node {
+			resolved, err := walkResolveSecrets(fmt.Sprintf("%s/%d", path, i), child, refs)
+			if err != nil {
+				return nil, err
+			}
+			node[i] = resolved
+		}
+		return node, nil
+	case string:
+		if !strings.HasPrefix(node, secretPrefix) {
+			return node, nil
+		}
+		resolved, err := resolveSecret(node)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s: %w", path, err)
+		}
+		refs[path] = node
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolveConfigSecrets resolves every secret:// reference anywhere in
+// config - any string value, not just the well-known broker credential
+// fields - remembering the original reference in config.secretRefs so
+// that WriteServiceConfig can persist it instead of the resolved
+// plaintext
+func resolveConfigSecrets(config *ServiceConfig) error {
+	tree, err := configToTree(*config)
+	if err != nil {
+		return err
+	}
+
+	refs := make(map[string]string)
+	resolvedTree, err := walkResolveSecrets("", tree, refs)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(resolvedTree)
+	if err != nil {
+		return err
+	}
+	var resolved ServiceConfig
+	if err := json.Unmarshal(data, &resolved); err != nil {
+		return err
+	}
+	if len(refs) > 0 {
+		resolved.secretRefs = refs
+	}
+	*config = resolved
+	return nil
+}
+
+// withSecretRefsRestored returns a copy of config where every value
+// resolved from a secret:// reference is set back to that reference
+func (config ServiceConfig) withSecretRefsRestored() ServiceConfig {
+	if len(config.secretRefs) == 0 {
+		return config
+	}
+
+	tree, err := configToTree(config)
+	if err != nil {
+		return config
+	}
+	for path, ref := range config.secretRefs {
+		setPointer(tree, path, ref)
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return config
+	}
+	var restored ServiceConfig
+	if err := json.Unmarshal(data, &restored); err != nil {
+		return config
+	}
+	restored.secretRefs = config.secretRefs
+	return restored
+}
+
+const redactedPlaceholder = "[REDACTED]"
+
+// Redacted returns a copy of config safe for logging: the broker
+// passwords are always masked, and so is any other value that was
+// resolved from a secret:// reference (e.g. a DB credential passed as
+// secret://...)
+func (config ServiceConfig) Redacted() ServiceConfig {
+	out := config
+	if out.LocalBroker.Password != "" {
+		out.LocalBroker.Password = redactedPlaceholder
+	}
+	if out.NetworkBroker.Password != "" {
+		out.NetworkBroker.Password = redactedPlaceholder
+	}
+
+	if len(out.secretRefs) > 0 {
+		if tree, err := configToTree(out); err == nil {
+			for path := range out.secretRefs {
+				setPointer(tree, path, redactedPlaceholder)
+			}
+			if data, err := json.Marshal(tree); err == nil {
+				var redacted ServiceConfig
+				if json.Unmarshal(data, &redacted) == nil {
+					out = redacted
+				}
+			}
+		}
+	}
+
+	out.secretRefs = nil
+	return out
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"testing"
+)
+
+func TestResolveConfigSecretsDBField(t *testing.T) {
+	os.Setenv("TEST_ENERGIEIP_DB_HOST", "db.internal")
+	defer os.Unsetenv("TEST_ENERGIEIP_DB_HOST")
+
+	config := ServiceConfig{
+		DB: DBConnector{
+			ClientIP:   "secret://env/TEST_ENERGIEIP_DB_HOST",
+			ClientPort: "8086",
+		},
+	}
+
+	if err := resolveConfigSecrets(&config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.DB.ClientIP != "db.internal" {
+		t.Fatalf("expected the DB.ClientIP secret ref to resolve, got %q", config.DB.ClientIP)
+	}
+	if config.secretRefs["/db/clientIp"] != "secret://env/TEST_ENERGIEIP_DB_HOST" {
+		t.Fatalf("expected the DB.ClientIP reference to be remembered, got %+v", config.secretRefs)
+	}
+
+	restored := config.withSecretRefsRestored()
+	if restored.DB.ClientIP != "secret://env/TEST_ENERGIEIP_DB_HOST" {
+		t.Fatalf("expected withSecretRefsRestored to restore the DB.ClientIP reference, got %q", restored.DB.ClientIP)
+	}
+
+	redacted := config.Redacted()
+	if redacted.DB.ClientIP != redactedPlaceholder {
+		t.Fatalf("expected Redacted to mask the DB.ClientIP secret, got %q", redacted.DB.ClientIP)
+	}
+}
+
+func TestResolveSecretPassthrough(t *testing.T) {
+	value, err := resolveSecret("plain-value")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "plain-value" {
+		t.Fatalf("expected passthrough value, got %q", value)
+	}
+}
+
+func TestResolveSecretEnv(t *testing.T) {
+	os.Setenv("TEST_ENERGIEIP_SECRET", "s3cr3t")
+	defer os.Unsetenv("TEST_ENERGIEIP_SECRET")
+
+	value, err := resolveSecret("secret://env/TEST_ENERGIEIP_SECRET")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "s3cr3t" {
+		t.Fatalf("expected s3cr3t, got %q", value)
+	}
+}
+
+func TestResolveSecretUnsetEnv(t *testing.T) {
+	os.Unsetenv("TEST_ENERGIEIP_SECRET_UNSET")
+	if _, err := resolveSecret("secret://env/TEST_ENERGIEIP_SECRET_UNSET"); err == nil {
+		t.Fatal("expected an error for an unset environment variable")
+	}
+}
+
+func TestResolveSecretUnknownProvider(t *testing.T) {
+	if _, err := resolveSecret("secret://vault/some-key"); err == nil {
+		t.Fatal("expected an error for an unknown provider")
+	}
+}
+
+func TestResolveSecretMalformed(t *testing.T) {
+	if _, err := resolveSecret("secret://env"); err == nil {
+		t.Fatal("expected an error for a malformed reference")
+	}
+}
+
+func TestRegisterSecretProvider(t *testing.T) {
+	RegisterSecretProvider("test-static", staticSecretProvider("from-test-provider"))
+	defer func() {
+		secretProvidersMu.Lock()
+		delete(secretProviders, "test-static")
+		secretProvidersMu.Unlock()
+	}()
+
+	value, err := resolveSecret("secret://test-static/anything")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "from-test-provider" {
+		t.Fatalf("expected from-test-provider, got %q", value)
+	}
+}
+
+type staticSecretProvider string
+
+func (p staticSecretProvider) Resolve(key string) (string, error) {
+	return string(p), nil
+}
+
+func TestDecryptAESGCMRoundTrip(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatalf("failed to build cipher: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("failed to build GCM: %v", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+
+	plaintext := "hunter2"
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	decrypted, err := decryptAESGCM(
+		base64.StdEncoding.EncodeToString(key),
+		base64.StdEncoding.EncodeToString(sealed),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decrypted != plaintext {
+		t.Fatalf("expected %q, got %q", plaintext, decrypted)
+	}
+}
+
+func TestDecryptAESGCMShortSealed(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	_, err := decryptAESGCM(
+		base64.StdEncoding.EncodeToString(key),
+		base64.StdEncoding.EncodeToString([]byte("short")),
+	)
+	if err == nil {
+		t.Fatal("expected an error for a sealed blob shorter than the nonce")
+	}
+}
@@ -1,12 +1,10 @@
 package service
 
 import (
-	"bytes"
 	"encoding/json"
 	"io/ioutil"
 	"os"
-	"os/exec"
-	"strings"
+	"time"
 )
 
 // IService definition
@@ -23,14 +21,27 @@ const (
 	ServiceStop    = "stopped"
 )
 
+//Restart policies for RunManaged, mirroring systemd's Restart= directive
+const (
+	RestartAlways    = "always"
+	RestartOnFailure = "on-failure"
+	RestartNever     = "no"
+)
+
 //Service description
 type Service struct {
-	Name        string        `json:"name"`
-	Systemd     []string      `json:"systemd"` //systemd service
-	Version     string        `json:"version"`
-	PackageName string        `json:"packageName"` //DebianPackageName
-	Config      ServiceConfig `json:"config"`
-	ConfigPath  string        `json:"configPath"`
+	Name           string        `json:"name"`
+	Systemd        []string      `json:"systemd"` //systemd service
+	Version        string        `json:"version"`
+	PackageName    string        `json:"packageName"`    //DebianPackageName
+	PackageManager string        `json:"packageManager"` //force apt/dpkg/dnf/pacman/apk/snap instead of autodetecting
+	Config         ServiceConfig `json:"config"`
+	ConfigPath     string        `json:"configPath"`
+
+	RestartPolicy string        `json:"restartPolicy"` //always/on-failure/no, see Restart* constants
+	RestartDelay  time.Duration `json:"restartDelay"`  //base delay before the first restart attempt
+	MaxRestarts   int           `json:"maxRestarts"`    //0 means unlimited
+	StartTimeout  time.Duration `json:"startTimeout"`   //time allowed for Initialize to return; Run is not bounded
 }
 
 //ServiceConfig desription
@@ -39,6 +50,11 @@ type ServiceConfig struct {
 	NetworkBroker Broker      `json:"networkBroker"`
 	DB            DBConnector `json:"db"`
 	LogLevel      string      `json:"logLevel"`
+
+	//secretRefs remembers which fields were resolved from a secret://
+	//reference, so WriteServiceConfig can persist the reference instead
+	//of the resolved plaintext
+	secretRefs map[string]string
 }
 
 //DBConnector description
@@ -89,12 +105,16 @@ func ReadServiceConfig(path string) (*ServiceConfig, error) {
 	if config.LogLevel == "" {
 		config.LogLevel = "INFO"
 	}
+	if err := resolveConfigSecrets(&config); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
 
-//WriteServiceConfig store configuration
+//WriteServiceConfig store configuration, preserving secret:// references
+//instead of the plaintext they were resolved to
 func WriteServiceConfig(path string, config ServiceConfig) error {
-	dump, err := config.ToJSON()
+	dump, err := config.withSecretRefsRestored().ToJSON()
 	if err != nil {
 		return err
 	}
@@ -123,62 +143,62 @@ func ToService(val interface{}) (*Service, error) {
 
 // GetServiceStatus return service status
 func (s Service) GetServiceStatus() string {
-	outputActive := &bytes.Buffer{}
-	cmd := exec.Command("systemctl", "is-active", s.Name)
-	cmd.Stdout = outputActive
-	cmd.Run()
-	output := strings.TrimSpace(string(outputActive.Bytes()))
-	switch output {
-	case "failed":
-		return ServiceFailed
-	case "active":
-		return ServiceRunning
-	default:
-		outputEnable := &bytes.Buffer{}
-		cmd = exec.Command("systemctl", "is-enabled", s.Name)
-		cmd.Stdout = outputEnable
-		cmd.Run()
-		output = strings.TrimSpace(string(outputEnable.Bytes()))
-		if output == "disabled" {
-			return ServiceStop
-		}
+	status, err := backend.Status(s)
+	if status != "" {
+		return status
+	}
+	if err != nil {
 		return ServiceMissing
 	}
+	return status
 }
 
 // Install install a given service
 func (s Service) Install() (string, error) {
-	cmd := exec.Command("apt-get", "install", "-y", s.PackageName)
-	out, err := cmd.CombinedOutput()
-	return string(out), err
+	return backend.Install(s)
 }
 
 // Remove a given service
 func (s Service) Remove() (string, error) {
-	cmd := exec.Command("apt-get", "remove", "-y", s.PackageName)
-	out, err := cmd.CombinedOutput()
-	return string(out), err
+	return backend.Remove(s)
 }
 
 // Start a given service
 func (s Service) Start() (string, error) {
-	cmd := exec.Command("systemctl", "start", s.Name)
-	out, err := cmd.CombinedOutput()
-	return string(out), err
+	return backend.Start(s)
 }
 
 // Stop a given service
 func (s Service) Stop() (string, error) {
-	cmd := exec.Command("systemctl", "stop", s.Name)
-	out, err := cmd.CombinedOutput()
-	return string(out), err
+	return backend.Stop(s)
 }
 
-//InstallPackages start all given services
-func InstallPackages(services map[string]Service) {
-	for _, service := range services {
-		service.Install()
+// Enable a given service so it starts on boot
+func (s Service) Enable() (string, error) {
+	return backend.Enable(s)
+}
+
+// Disable a given service so it no longer starts on boot
+func (s Service) Disable() (string, error) {
+	return backend.Disable(s)
+}
+
+// Reload a given service, restarting it if the backend has no
+// dedicated reload operation
+func (s Service) Reload() (string, error) {
+	return backend.Reload(s)
+}
+
+//InstallPackages install all given services, returning the errors
+//encountered per service name rather than swallowing them
+func InstallPackages(services map[string]Service) map[string]error {
+	errs := make(map[string]error)
+	for name, service := range services {
+		if _, err := service.Install(); err != nil {
+			errs[name] = err
+		}
 	}
+	return errs
 }
 
 //StartServices start all given services
@@ -191,34 +211,25 @@ func StartServices(services map[string]Service) {
 	}
 }
 
-//RemoveServices remove all given services
-func RemoveServices(services map[string]Service) {
-	for _, service := range services {
+//RemoveServices stop and remove all given services, returning the errors
+//encountered per service name rather than swallowing them
+func RemoveServices(services map[string]Service) map[string]error {
+	errs := make(map[string]error)
+	for name, service := range services {
 		service.Stop()
-		service.Remove()
+		if _, err := service.Remove(); err != nil {
+			errs[name] = err
+		}
 	}
+	return errs
 }
 
-//GetPackageVersion return package version
+//GetPackageVersion return package version using the autodetected
+//PackageManager for this host
 func GetPackageVersion(service string) *string {
-	cmd := exec.Command("apt", "show", service)
-	cmdOutput := &bytes.Buffer{}
-	cmd.Stdout = cmdOutput
-	err := cmd.Run()
-	if err != nil {
+	pm := DetectPackageManager()
+	if pm == nil {
 		return nil
 	}
-	output := string(cmdOutput.Bytes())
-	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
-		if !strings.HasPrefix(line, "Version:") {
-			continue
-		}
-		lineSplit := strings.Split(line, " ")
-		if len(lineSplit) > 1 {
-			version := lineSplit[1]
-			return &version
-		}
-	}
-
-	return nil
+	return pm.Version(service)
 }
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxBackoffSteps bounds the exponential backoff growth so that a large
+// MaxRestarts cannot overflow RestartDelay*2^n into an unreasonable sleep
+const maxBackoffSteps = 10
+
+// RunManaged runs svc under a systemd-style restart policy: it calls
+// Initialize then Run, and on exit reschedules according to s.RestartPolicy
+// with an exponential backoff starting at s.RestartDelay and capped at
+// s.RestartDelay*2^maxBackoffSteps. It returns when ctx is cancelled, when
+// s.MaxRestarts is reached, or when the policy does not call for a restart.
+// Cancelling ctx calls svc.Stop() to unblock a live svc.Run()
+func (s Service) RunManaged(ctx context.Context, svc IService) error {
+	attempt := 0
+	for {
+		err := s.runOnce(ctx, svc)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if !s.shouldRestart(err, attempt) {
+			return err
+		}
+
+		delay := s.backoffDelay(attempt)
+		attempt++
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// runOnce calls Initialize then Run once, calling svc.Stop() if ctx is
+// cancelled while either is in flight
+func (s Service) runOnce(ctx context.Context, svc IService) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			svc.Stop()
+		case <-done:
+		}
+	}()
+
+	if err := s.initialize(svc); err != nil {
+		return err
+	}
+	return svc.Run()
+}
+
+// initialize calls svc.Initialize, failing it out after s.StartTimeout
+// if set rather than blocking RunManaged's restart loop forever on a
+// service that never reports as started
+func (s Service) initialize(svc IService) error {
+	if s.StartTimeout <= 0 {
+		return svc.Initialize(s.ConfigPath)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- svc.Initialize(s.ConfigPath) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(s.StartTimeout):
+		return fmt.Errorf("service %s did not initialize within %s", s.Name, s.StartTimeout)
+	}
+}
+
+func (s Service) shouldRestart(err error, attempt int) bool {
+	if s.MaxRestarts > 0 && attempt >= s.MaxRestarts {
+		return false
+	}
+	switch s.RestartPolicy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+func (s Service) backoffDelay(attempt int) time.Duration {
+	if s.RestartDelay <= 0 {
+		return 0
+	}
+	steps := attempt
+	if steps > maxBackoffSteps {
+		steps = maxBackoffSteps
+	}
+	return s.RestartDelay * time.Duration(uint64(1)<<uint(steps))
+}
+
+// WatchStatus polls GetServiceStatus every interval and emits a
+// ServiceStatus each time the reported state changes, until ctx is
+// cancelled. The returned channel is closed when polling stops
+func (s Service) WatchStatus(ctx context.Context, interval time.Duration) <-chan ServiceStatus {
+	out := make(chan ServiceStatus)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := ""
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				status := s.GetServiceStatus()
+				if status == last {
+					continue
+				}
+				last = status
+				select {
+				case out <- ServiceStatus{Service: s, Status: &status}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
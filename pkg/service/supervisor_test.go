@@ -0,0 +1,64 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	s := Service{RestartDelay: time.Second}
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 1, want: 2 * time.Second},
+		{attempt: 2, want: 4 * time.Second},
+		{attempt: maxBackoffSteps, want: time.Second * time.Duration(uint64(1)<<maxBackoffSteps)},
+		{attempt: maxBackoffSteps + 5, want: time.Second * time.Duration(uint64(1)<<maxBackoffSteps)},
+	}
+	for _, c := range cases {
+		if got := s.backoffDelay(c.attempt); got != c.want {
+			t.Fatalf("backoffDelay(%d) = %s, want %s", c.attempt, got, c.want)
+		}
+	}
+}
+
+func TestBackoffDelayDisabled(t *testing.T) {
+	s := Service{RestartDelay: 0}
+	if got := s.backoffDelay(3); got != 0 {
+		t.Fatalf("expected a zero delay when RestartDelay is unset, got %s", got)
+	}
+}
+
+func TestShouldRestart(t *testing.T) {
+	failure := errors.New("boom")
+
+	cases := []struct {
+		name    string
+		policy  string
+		err     error
+		attempt int
+		max     int
+		want    bool
+	}{
+		{name: "always restarts on success", policy: RestartAlways, err: nil, want: true},
+		{name: "always restarts on failure", policy: RestartAlways, err: failure, want: true},
+		{name: "on-failure skips a clean exit", policy: RestartOnFailure, err: nil, want: false},
+		{name: "on-failure restarts after an error", policy: RestartOnFailure, err: failure, want: true},
+		{name: "never restarts", policy: RestartNever, err: failure, want: false},
+		{name: "unknown policy behaves like never", policy: "", err: failure, want: false},
+		{name: "max restarts boundary is exclusive", policy: RestartAlways, err: nil, attempt: 3, max: 3, want: false},
+		{name: "below the max restarts boundary", policy: RestartAlways, err: nil, attempt: 2, max: 3, want: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := Service{RestartPolicy: c.policy, MaxRestarts: c.max}
+			if got := s.shouldRestart(c.err, c.attempt); got != c.want {
+				t.Fatalf("shouldRestart(%v, %d) = %v, want %v", c.err, c.attempt, got, c.want)
+			}
+		})
+	}
+}